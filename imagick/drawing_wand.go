@@ -0,0 +1,54 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+*/
+import "C"
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// drawingWandCounter tracks the number of live DrawingWand instances so
+// tests can assert that every drawing wand created during a run was
+// destroyed.
+var drawingWandCounter int64
+
+// DrawingWand is a Go wrapper around the ImageMagick DrawingWand C API,
+// used to describe a set of vector drawing operations and settings.
+type DrawingWand struct {
+	wand *C.DrawingWand
+}
+
+// NewDrawingWand returns a new DrawingWand with default settings.
+func NewDrawingWand() *DrawingWand {
+	dw := &DrawingWand{wand: C.NewDrawingWand()}
+	atomic.AddInt64(&drawingWandCounter, 1)
+	runtime.SetFinalizer(dw, func(dw *DrawingWand) {
+		dw.Destroy()
+	})
+	return dw
+}
+
+// Destroy deallocates the underlying DrawingWand. It is safe to call
+// Destroy more than once.
+func (dw *DrawingWand) Destroy() {
+	if dw.wand == nil {
+		return
+	}
+	dw.wand = C.DestroyDrawingWand(dw.wand)
+	atomic.AddInt64(&drawingWandCounter, -1)
+	runtime.SetFinalizer(dw, nil)
+}
+
+// IsVerified returns whether the wand's underlying C object is a valid,
+// non-destroyed DrawingWand instance.
+func (dw *DrawingWand) IsVerified() bool {
+	return dw.wand != nil && C.IsDrawingWand(dw.wand) != C.MagickFalse
+}
@@ -0,0 +1,44 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"github.com/yoya/imagick/imagick/engine"
+)
+
+// Engine abstracts the handful of high-level operations (decode, resize,
+// rotate, re-encode, raw pixel export) that used to be invoked directly
+// on a *MagickWand. It is defined in the cgo-free imagick/engine
+// package; see that package's doc comment for why. This alias lets
+// existing callers keep writing imagick.Engine.
+type Engine = engine.Engine
+
+// NewEngine returns the Engine best suited to handle format: a
+// BuiltinEngine when format is trivially handled by the Go standard
+// library codecs or ImageMagick is unavailable in this build, and a
+// MagickEngine otherwise. format is matched case-insensitively against
+// the usual ImageMagick format names (e.g. "JPEG", "PNG"); pass "" if
+// the format is not known yet, which always selects MagickEngine when
+// available since BuiltinEngine cannot sniff a format it hasn't decoded.
+func NewEngine(format string) Engine {
+	return engine.NewEngine(format)
+}
+
+// EngineAuto is an Engine that defers to NewEngine to pick between
+// MagickEngine and BuiltinEngine the first time it is used, then
+// forwards every subsequent call to whichever one it picked.
+type EngineAuto = engine.EngineAuto
+
+// NewEngineAuto returns an EngineAuto that will select its underlying
+// Engine based on format, using the same rule as NewEngine.
+func NewEngineAuto(format string) *EngineAuto {
+	return engine.NewEngineAuto(format)
+}
+
+// NewBuiltinEngine returns an Engine implemented using only the Go
+// standard library image codecs, with no ImageMagick dependency at all.
+func NewBuiltinEngine() *engine.BuiltinEngine {
+	return engine.NewBuiltinEngine()
+}
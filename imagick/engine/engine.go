@@ -0,0 +1,158 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package engine abstracts the handful of high-level image operations
+// (decode, resize, rotate, re-encode, raw pixel export) that used to be
+// invoked directly on a *imagick.MagickWand, so that a program which only
+// ever touches JPEG/PNG/GIF can ship without a system ImageMagick install
+// at all. This package itself imports no cgo: BuiltinEngine is pure Go
+// standard library image codecs, and MagickEngine support is wired in,
+// if present, only through MagickFactory, which the cgo-backed imagick
+// package registers from its own init function. A binary that never
+// imports imagick therefore builds and runs with no MagickWand
+// dependency whatsoever, falling back to BuiltinEngine for every format.
+package engine
+
+import (
+	"io"
+	"strings"
+)
+
+// Engine abstracts the handful of high-level operations (decode, resize,
+// rotate, re-encode, raw pixel export) that used to be invoked directly
+// on a *MagickWand. MagickEngine (registered via MagickFactory by the
+// cgo imagick package) implements it with the ImageMagick bindings;
+// BuiltinEngine implements it with the Go standard library image codecs
+// instead, so a program that only touches JPEG/PNG/GIF never calls into
+// ImageMagick at runtime, and need not even link it in.
+type Engine interface {
+	// Read decodes an image from r, replacing any image the engine
+	// currently holds.
+	Read(r io.Reader) error
+
+	// Resize scales the current image to width x height.
+	Resize(width, height uint) error
+
+	// Rotate rotates the current image clockwise by degrees.
+	Rotate(degrees float64) error
+
+	// Format returns the format the current image was decoded from
+	// (e.g. "JPEG"), or "" if no image has been read yet.
+	Format() string
+
+	// Encode writes the current image to w in format.
+	Encode(w io.Writer, format string) error
+
+	// ExportImagePixels extracts the pixel data for the rectangle (x,
+	// y, width, height), in the channel order described by m (e.g.
+	// "RGB", "RGBA") and the given storage type.
+	ExportImagePixels(x, y int, width, height uint, m string, storage StorageType) (interface{}, error)
+
+	// Close releases any resources (C memory, file handles, ...) held
+	// by the engine. It is safe to call Close more than once.
+	Close() error
+}
+
+// builtinFormats lists the formats BuiltinEngine can decode and encode
+// using only the Go standard library image codecs.
+var builtinFormats = map[string]bool{
+	"JPEG": true,
+	"JPG":  true,
+	"PNG":  true,
+	"GIF":  true,
+}
+
+// MagickFactory, if non-nil, constructs a MagickEngine-backed Engine.
+// It is nil until the cgo-backed imagick package is imported, which
+// registers it from an init function; programs that only import
+// imagick/engine never set it and so never pull in the MagickWand
+// toolchain.
+var MagickFactory func() Engine
+
+// IsMagickAvailable reports whether a cgo-backed MagickEngine
+// implementation has registered itself via MagickFactory. It is false
+// for any binary that does not import the imagick package, in which
+// case NewEngine and EngineAuto fall back to BuiltinEngine for every
+// format.
+func IsMagickAvailable() bool {
+	return MagickFactory != nil
+}
+
+// NewEngine returns the Engine best suited to handle format: a
+// BuiltinEngine when format is trivially handled by the Go standard
+// library codecs or ImageMagick is unavailable in this build, and a
+// MagickEngine otherwise. format is matched case-insensitively against
+// the usual ImageMagick format names (e.g. "JPEG", "PNG"); pass "" if
+// the format is not known yet, which always selects MagickEngine when
+// available since BuiltinEngine cannot sniff a format it hasn't decoded.
+func NewEngine(format string) Engine {
+	if builtinFormats[strings.ToUpper(format)] || !IsMagickAvailable() {
+		return NewBuiltinEngine()
+	}
+	return MagickFactory()
+}
+
+// EngineAuto is an Engine that defers to NewEngine to pick between
+// MagickEngine and BuiltinEngine the first time it is used, then
+// forwards every subsequent call to whichever one it picked.
+type EngineAuto struct {
+	format string
+	engine Engine
+}
+
+// NewEngineAuto returns an EngineAuto that will select its underlying
+// Engine based on format, using the same rule as NewEngine.
+func NewEngineAuto(format string) *EngineAuto {
+	return &EngineAuto{format: format}
+}
+
+// selected returns the underlying Engine, choosing and caching it on
+// first use.
+func (a *EngineAuto) selected() Engine {
+	if a.engine == nil {
+		a.engine = NewEngine(a.format)
+	}
+	return a.engine
+}
+
+// Read implements Engine.
+func (a *EngineAuto) Read(r io.Reader) error {
+	return a.selected().Read(r)
+}
+
+// Resize implements Engine.
+func (a *EngineAuto) Resize(width, height uint) error {
+	return a.selected().Resize(width, height)
+}
+
+// Rotate implements Engine.
+func (a *EngineAuto) Rotate(degrees float64) error {
+	return a.selected().Rotate(degrees)
+}
+
+// Format implements Engine.
+func (a *EngineAuto) Format() string {
+	return a.selected().Format()
+}
+
+// Encode implements Engine.
+func (a *EngineAuto) Encode(w io.Writer, format string) error {
+	return a.selected().Encode(w, format)
+}
+
+// ExportImagePixels implements Engine.
+func (a *EngineAuto) ExportImagePixels(x, y int, width, height uint, m string, storage StorageType) (interface{}, error) {
+	return a.selected().ExportImagePixels(x, y, width, height, m, storage)
+}
+
+// Close implements Engine. It is a no-op if no other method was ever
+// called, since no underlying Engine was then selected.
+func (a *EngineAuto) Close() error {
+	if a.engine == nil {
+		return nil
+	}
+	return a.engine.Close()
+}
+
+var _ Engine = (*EngineAuto)(nil)
@@ -0,0 +1,194 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+)
+
+// BuiltinEngine implements Engine using only the Go standard library
+// image codecs (JPEG, PNG, GIF): reading, resizing, rotating and
+// re-encoding an image through BuiltinEngine never calls into
+// ImageMagick, and this file has no cgo dependency of its own.
+type BuiltinEngine struct {
+	img    image.Image
+	format string
+}
+
+// NewBuiltinEngine returns an empty BuiltinEngine.
+func NewBuiltinEngine() *BuiltinEngine {
+	return &BuiltinEngine{}
+}
+
+// Read implements Engine.
+func (e *BuiltinEngine) Read(r io.Reader) error {
+	img, format, err := image.Decode(r)
+	if err != nil {
+		return err
+	}
+	e.img = img
+	e.format = strings.ToUpper(format)
+	return nil
+}
+
+// Resize implements Engine. It uses nearest-neighbor sampling, which is
+// enough for the thumbnail-grade resizes BuiltinEngine targets; callers
+// who need higher-quality resampling should use MagickEngine instead.
+func (e *BuiltinEngine) Resize(width, height uint) error {
+	if e.img == nil {
+		return fmt.Errorf("imagick: BuiltinEngine.Resize called before Read")
+	}
+
+	src := e.img
+	srcBounds := src.Bounds()
+	srcW, srcH := srcBounds.Dx(), srcBounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+
+	for y := 0; y < int(height); y++ {
+		sy := srcBounds.Min.Y + y*srcH/int(height)
+		for x := 0; x < int(width); x++ {
+			sx := srcBounds.Min.X + x*srcW/int(width)
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+
+	e.img = dst
+	return nil
+}
+
+// Rotate implements Engine. Only multiples of 90 degrees are supported;
+// arbitrary-angle rotation needs an affine transform and interpolation
+// that BuiltinEngine, by design, leaves to MagickEngine.
+func (e *BuiltinEngine) Rotate(degrees float64) error {
+	if e.img == nil {
+		return fmt.Errorf("imagick: BuiltinEngine.Rotate called before Read")
+	}
+
+	if math.Mod(degrees, 90) != 0 {
+		return fmt.Errorf("imagick: BuiltinEngine only supports rotation in multiples of 90 degrees, got %v", degrees)
+	}
+
+	turns := int(degrees/90) % 4
+	if turns < 0 {
+		turns += 4
+	}
+
+	for i := 0; i < turns; i++ {
+		e.img = rotate90(e.img)
+	}
+	return nil
+}
+
+// rotate90 returns a copy of img rotated 90 degrees clockwise.
+func rotate90(img image.Image) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, h, w))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dst.Set(h-1-y, x, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// Format implements Engine.
+func (e *BuiltinEngine) Format() string {
+	return e.format
+}
+
+// Encode implements Engine.
+func (e *BuiltinEngine) Encode(w io.Writer, format string) error {
+	if e.img == nil {
+		return fmt.Errorf("imagick: BuiltinEngine.Encode called before Read")
+	}
+
+	switch strings.ToUpper(format) {
+	case "JPEG", "JPG":
+		return jpeg.Encode(w, e.img, nil)
+	case "PNG":
+		return png.Encode(w, e.img)
+	case "GIF":
+		return gif.Encode(w, e.img, nil)
+	default:
+		return fmt.Errorf("imagick: BuiltinEngine does not support encoding format %q", format)
+	}
+}
+
+// ExportImagePixels implements Engine for the channel maps and storage
+// types BuiltinEngine supports: any combination of the letters R, G, B
+// and A in m, with storage PIXEL_CHAR, PIXEL_FLOAT or PIXEL_DOUBLE.
+func (e *BuiltinEngine) ExportImagePixels(x, y int, width, height uint, m string, storage StorageType) (interface{}, error) {
+	if e.img == nil {
+		return nil, fmt.Errorf("imagick: BuiltinEngine.ExportImagePixels called before Read")
+	}
+
+	channels := strings.ToUpper(m)
+	count := int(width) * int(height) * len(channels)
+
+	switch storage {
+	case PIXEL_CHAR:
+		out := make([]byte, 0, count)
+		err := e.forEachPixel(x, y, width, height, channels, func(v float64) {
+			out = append(out, byte(v*255+0.5))
+		})
+		return out, err
+	case PIXEL_FLOAT:
+		out := make([]float32, 0, count)
+		err := e.forEachPixel(x, y, width, height, channels, func(v float64) {
+			out = append(out, float32(v))
+		})
+		return out, err
+	case PIXEL_DOUBLE:
+		out := make([]float64, 0, count)
+		err := e.forEachPixel(x, y, width, height, channels, func(v float64) {
+			out = append(out, v)
+		})
+		return out, err
+	default:
+		return nil, fmt.Errorf("imagick: BuiltinEngine does not support storage type %v", storage)
+	}
+}
+
+// forEachPixel walks the rectangle (x, y, width, height) row-major,
+// emitting each requested channel of every pixel, normalized to [0, 1],
+// through emit.
+func (e *BuiltinEngine) forEachPixel(x, y int, width, height uint, channels string, emit func(float64)) error {
+	bounds := e.img.Bounds()
+	for dy := 0; dy < int(height); dy++ {
+		for dx := 0; dx < int(width); dx++ {
+			r, g, b, a := e.img.At(bounds.Min.X+x+dx, bounds.Min.Y+y+dy).RGBA()
+			for _, c := range channels {
+				switch c {
+				case 'R':
+					emit(float64(r) / 0xffff)
+				case 'G':
+					emit(float64(g) / 0xffff)
+				case 'B':
+					emit(float64(b) / 0xffff)
+				case 'A':
+					emit(float64(a) / 0xffff)
+				default:
+					return fmt.Errorf("imagick: BuiltinEngine does not support channel %q", string(c))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Close implements Engine.
+func (e *BuiltinEngine) Close() error {
+	e.img = nil
+	return nil
+}
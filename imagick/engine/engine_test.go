@@ -0,0 +1,142 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package engine
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func samplePNG(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 16), G: uint8(y * 16), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err.Error())
+	}
+	return buf.Bytes()
+}
+
+func TestBuiltinEngineReadResizeEncode(t *testing.T) {
+	e := NewBuiltinEngine()
+	defer e.Close()
+
+	if err := e.Read(bytes.NewReader(samplePNG(t))); err != nil {
+		t.Fatal(err.Error())
+	}
+	if e.Format() != "PNG" {
+		t.Fatalf("Expected format PNG; Got %s", e.Format())
+	}
+
+	if err := e.Resize(4, 2); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var out bytes.Buffer
+	if err := e.Encode(&out, "PNG"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.Len() == 0 {
+		t.Fatal("Encode wrote zero bytes")
+	}
+}
+
+func TestBuiltinEngineRotate90(t *testing.T) {
+	e := NewBuiltinEngine()
+	defer e.Close()
+
+	if err := e.Read(bytes.NewReader(samplePNG(t))); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := e.Rotate(90); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := e.Rotate(45); err == nil {
+		t.Fatal("Expected an error rotating by a non-multiple of 90 degrees")
+	}
+}
+
+func TestBuiltinEngineRotateOutOfRangeMultiples(t *testing.T) {
+	for _, degrees := range []float64{-90, -180, -270, 360} {
+		e := NewBuiltinEngine()
+		if err := e.Read(bytes.NewReader(samplePNG(t))); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := e.Rotate(degrees); err != nil {
+			t.Fatalf("Rotate(%v): expected no error for an exact multiple of 90; Got %v", degrees, err)
+		}
+		e.Close()
+	}
+}
+
+func TestBuiltinEngineExportImagePixels(t *testing.T) {
+	e := NewBuiltinEngine()
+	defer e.Close()
+
+	if err := e.Read(bytes.NewReader(samplePNG(t))); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	val, err := e.ExportImagePixels(0, 0, 8, 4, "RGBA", PIXEL_CHAR)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	pixels := val.([]byte)
+	if len(pixels) != 8*4*4 {
+		t.Fatalf("Expected %d bytes; Got %d", 8*4*4, len(pixels))
+	}
+}
+
+func TestNewEnginePicksBuiltinForPNG(t *testing.T) {
+	e := NewEngine("PNG")
+	defer e.Close()
+
+	if _, ok := e.(*BuiltinEngine); !ok {
+		t.Fatalf("Expected NewEngine(\"PNG\") to return a *BuiltinEngine; Got %T", e)
+	}
+}
+
+func TestNewEnginePicksBuiltinWhenMagickUnavailable(t *testing.T) {
+	if MagickFactory != nil {
+		t.Skip("MagickFactory is registered in this test binary; covered by the imagick package instead")
+	}
+
+	e := NewEngine("HEIC")
+	defer e.Close()
+
+	if _, ok := e.(*BuiltinEngine); !ok {
+		t.Fatalf("Expected NewEngine(\"HEIC\") to fall back to *BuiltinEngine with no MagickFactory registered; Got %T", e)
+	}
+}
+
+func TestEngineAutoSelectsOnce(t *testing.T) {
+	a := NewEngineAuto("PNG")
+	defer a.Close()
+
+	if err := a.Read(bytes.NewReader(samplePNG(t))); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, ok := a.selected().(*BuiltinEngine); !ok {
+		t.Fatalf("Expected EngineAuto(\"PNG\") to select a *BuiltinEngine; Got %T", a.selected())
+	}
+
+	// A second call must reuse the engine picked on the first one
+	// rather than re-selecting.
+	first := a.selected()
+	if a.selected() != first {
+		t.Fatal("Expected EngineAuto to cache its selected Engine")
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package engine
+
+// StorageType represents the MagickCore "StorageType" enumeration used by
+// the pixel import/export APIs to describe the Go type backing a pixel
+// buffer. The values below are hardcoded to match MagickCore's own C enum
+// rather than pulled in via cgo, so this package stays free of the
+// MagickWand toolchain dependency. The parent imagick package casts them
+// directly to C.StorageType when calling
+// MagickExportImagePixels/MagickImportImagePixels; nothing checks them
+// against the C header at build time.
+type StorageType int
+
+const (
+	PIXEL_UNDEFINED StorageType = 0
+	PIXEL_CHAR      StorageType = 1
+	PIXEL_DOUBLE    StorageType = 2
+	PIXEL_FLOAT     StorageType = 3
+	PIXEL_INTEGER   StorageType = 4
+	PIXEL_LONG      StorageType = 5
+	PIXEL_QUANTUM   StorageType = 6
+	PIXEL_SHORT     StorageType = 7
+)
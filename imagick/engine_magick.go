@@ -0,0 +1,101 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"io"
+	"io/ioutil"
+	"unsafe"
+
+	"github.com/yoya/imagick/imagick/engine"
+)
+
+// MagickEngine implements Engine on top of the cgo ImageMagick bindings,
+// giving access to every format and operation ImageMagick supports.
+type MagickEngine struct {
+	mw *MagickWand
+}
+
+// NewMagickEngine returns an Engine backed by a fresh MagickWand.
+func NewMagickEngine() *MagickEngine {
+	return &MagickEngine{mw: NewMagickWand()}
+}
+
+// init registers MagickEngine with imagick/engine so that NewEngine and
+// EngineAuto (which live in that cgo-free package) can select it when
+// this package is linked in. Programs that only import imagick/engine,
+// and never this package, never run this init and so never pull in the
+// MagickWand toolchain.
+func init() {
+	engine.MagickFactory = func() engine.Engine {
+		return NewMagickEngine()
+	}
+}
+
+// Read implements Engine.
+func (e *MagickEngine) Read(r io.Reader) error {
+	blob, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return e.mw.ReadImageBlob(blob)
+}
+
+// Resize implements Engine.
+func (e *MagickEngine) Resize(width, height uint) error {
+	return e.mw.ScaleImage(width, height)
+}
+
+// Rotate implements Engine.
+func (e *MagickEngine) Rotate(degrees float64) error {
+	background := NewPixelWand()
+	defer background.Destroy()
+
+	if C.MagickRotateImage(e.mw.wand, background.wand, C.double(degrees)) == C.MagickFalse {
+		return e.mw.GetLastError()
+	}
+	return nil
+}
+
+// Format implements Engine.
+func (e *MagickEngine) Format() string {
+	cformat := C.MagickGetImageFormat(e.mw.wand)
+	if cformat == nil {
+		return ""
+	}
+	defer relinquishMemory(unsafe.Pointer(cformat))
+	return C.GoString(cformat)
+}
+
+// Encode implements Engine.
+func (e *MagickEngine) Encode(w io.Writer, format string) error {
+	return e.mw.WriteImageStream(w, format)
+}
+
+// ExportImagePixels implements Engine.
+func (e *MagickEngine) ExportImagePixels(x, y int, width, height uint, m string, storage StorageType) (interface{}, error) {
+	return e.mw.ExportImagePixels(x, y, width, height, m, storage)
+}
+
+// Close implements Engine.
+func (e *MagickEngine) Close() error {
+	e.mw.Destroy()
+	return nil
+}
+
+// IsMagickAvailable reports whether a cgo-backed MagickEngine
+// implementation has registered itself via imagick/engine's
+// MagickFactory. It is always true once this package (which registers
+// MagickEngine from its init function) has been imported.
+func IsMagickAvailable() bool {
+	return engine.IsMagickAvailable()
+}
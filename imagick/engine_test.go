@@ -0,0 +1,29 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"testing"
+)
+
+// Tests that only need BuiltinEngine, or EngineAuto driven purely by
+// BuiltinEngine, live in imagick/engine instead, since they don't need
+// the MagickWand toolchain this package depends on. This file keeps the
+// one case that genuinely needs both engines linked together: verifying
+// that NewEngine picks MagickEngine once this package has registered it.
+func TestNewEnginePicksMagickForUnknownFormat(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	e := NewEngine("HEIC")
+	defer e.Close()
+
+	if _, ok := e.(*MagickEngine); !ok {
+		t.Fatalf("Expected NewEngine(\"HEIC\") to return a *MagickEngine; Got %T", e)
+	}
+}
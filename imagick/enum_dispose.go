@@ -0,0 +1,22 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#include <wand/MagickWand.h>
+*/
+import "C"
+
+// DisposeType represents the C "DisposeType" enumeration, describing how
+// an animated image's frame should be treated before the next frame is
+// rendered.
+type DisposeType int
+
+const (
+	DISPOSE_UNDEFINED  DisposeType = C.UndefinedDispose
+	DISPOSE_NONE       DisposeType = C.NoneDispose
+	DISPOSE_BACKGROUND DisposeType = C.BackgroundDispose
+	DISPOSE_PREVIOUS   DisposeType = C.PreviousDispose
+)
@@ -0,0 +1,38 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#include <wand/MagickWand.h>
+*/
+import "C"
+
+// OrientationType represents the C "OrientationType" enumeration, which
+// mirrors the EXIF "Orientation" tag values.
+type OrientationType int
+
+const (
+	ORIENTATION_UNDEFINED   OrientationType = C.UndefinedOrientation
+	ORIENTATION_TOPLEFT     OrientationType = C.TopLeftOrientation
+	ORIENTATION_TOPRIGHT    OrientationType = C.TopRightOrientation
+	ORIENTATION_BOTTOMRIGHT OrientationType = C.BottomRightOrientation
+	ORIENTATION_BOTTOMLEFT  OrientationType = C.BottomLeftOrientation
+	ORIENTATION_LEFTTOP     OrientationType = C.LeftTopOrientation
+	ORIENTATION_RIGHTTOP    OrientationType = C.RightTopOrientation
+	ORIENTATION_RIGHTBOTTOM OrientationType = C.RightBottomOrientation
+	ORIENTATION_LEFTBOTTOM  OrientationType = C.LeftBottomOrientation
+)
+
+// needsDimensionSwap reports whether o represents a 90 or 270 degree
+// rotation, meaning an image's width and height trade places once the
+// orientation is applied.
+func (o OrientationType) needsDimensionSwap() bool {
+	switch o {
+	case ORIENTATION_LEFTTOP, ORIENTATION_RIGHTTOP, ORIENTATION_RIGHTBOTTOM, ORIENTATION_LEFTBOTTOM:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"github.com/yoya/imagick/imagick/engine"
+)
+
+// StorageType represents the C "StorageType" enumeration used by the
+// pixel import/export APIs to describe the Go type backing a pixel
+// buffer. It is defined in the cgo-free imagick/engine package, which
+// hardcodes the numeric values to match MagickCore's C enum; this alias
+// lets existing callers keep writing imagick.StorageType.
+type StorageType = engine.StorageType
+
+const (
+	PIXEL_UNDEFINED = engine.PIXEL_UNDEFINED
+	PIXEL_CHAR      = engine.PIXEL_CHAR
+	PIXEL_DOUBLE    = engine.PIXEL_DOUBLE
+	PIXEL_FLOAT     = engine.PIXEL_FLOAT
+	PIXEL_INTEGER   = engine.PIXEL_INTEGER
+	PIXEL_LONG      = engine.PIXEL_LONG
+	PIXEL_QUANTUM   = engine.PIXEL_QUANTUM
+	PIXEL_SHORT     = engine.PIXEL_SHORT
+)
@@ -0,0 +1,83 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// jpegWithOrientation encodes a tiny JPEG fixture and splices in a
+// minimal EXIF APP1 segment carrying a single Orientation tag, for tests
+// that need a real JPEG with a known EXIF orientation without a binary
+// fixture file on disk. The JPEG bytes themselves come from the Go
+// standard library encoder; only the hand-built EXIF payload is new, and
+// TIFF/EXIF IFD layout is fixed enough (an II/42 header, a one-entry
+// IFD0 with an inline SHORT value) to write out directly.
+func jpegWithOrientation(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 64), G: uint8(y * 64), B: 200, A: 255})
+		}
+	}
+
+	var jpegBuf bytes.Buffer
+	if err := jpeg.Encode(&jpegBuf, img, nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	jpegBytes := jpegBuf.Bytes()
+
+	app1 := exifOrientationSegment(orientation)
+
+	// SOI (FFD8) is the first two bytes of every JPEG; splice the APP1
+	// marker in right after it and before whatever markers the stdlib
+	// encoder wrote.
+	out := make([]byte, 0, len(jpegBytes)+len(app1))
+	out = append(out, jpegBytes[:2]...)
+	out = append(out, app1...)
+	out = append(out, jpegBytes[2:]...)
+	return out
+}
+
+// exifOrientationSegment builds a complete "FF E1 <len> Exif\0\0 <TIFF>"
+// APP1 marker segment containing a single IFD0 entry: tag 0x0112
+// (Orientation), type 3 (SHORT), count 1, value orientation.
+func exifOrientationSegment(orientation uint16) []byte {
+	var tiff bytes.Buffer
+
+	// TIFF header: little-endian byte order, magic 42, IFD0 at offset 8.
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+
+	// IFD0: one entry, tag 0x0112 (Orientation), type 3 (SHORT), count 1.
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	binary.Write(&tiff, binary.LittleEndian, uint16(0x0112))
+	binary.Write(&tiff, binary.LittleEndian, uint16(3))
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+
+	// SHORT values <= 4 bytes are stored inline, left-justified for
+	// Intel byte order, followed by two bytes of padding.
+	binary.Write(&tiff, binary.LittleEndian, orientation)
+	binary.Write(&tiff, binary.LittleEndian, uint16(0))
+
+	// No next IFD.
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	var segment bytes.Buffer
+	segment.Write([]byte{0xFF, 0xE1})
+	binary.Write(&segment, binary.BigEndian, uint16(len(payload)+2))
+	segment.Write(payload)
+	return segment.Bytes()
+}
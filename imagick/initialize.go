@@ -0,0 +1,60 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Initialize initializes the MagickWand environment. This function
+// should be called before any other function in the MagickWand interface
+// is called, and it must be balanced by a call to Terminate before the
+// calling process exits.
+func Initialize() {
+	C.MagickWandGenesis()
+}
+
+// Terminate terminates the MagickWand environment. This function must be
+// called once for every call to Initialize.
+func Terminate() {
+	C.MagickWandTerminus()
+}
+
+// GetQuantumDepth returns the ImageMagick quantum depth name (e.g. "Q16")
+// along with the numeric depth in bits.
+func GetQuantumDepth() (name string, depth uint) {
+	var cdepth C.size_t
+	cname := C.MagickGetQuantumDepth(&cdepth)
+	defer relinquishMemory(unsafe.Pointer(cname))
+	return C.GoString(cname), uint(cdepth)
+}
+
+// GetQuantumRange returns the ImageMagick quantum range name along with
+// the numeric range.
+func GetQuantumRange() (name string, qrange uint) {
+	var cqrange C.size_t
+	cname := C.MagickGetQuantumRange(&cqrange)
+	defer relinquishMemory(unsafe.Pointer(cname))
+	return C.GoString(cname), uint(cqrange)
+}
+
+// GetVersion returns the ImageMagick API version as a string and as a
+// number.
+func GetVersion() (version string, versionNumber int) {
+	var cversionNumber C.size_t
+	cversion := C.MagickGetVersion(&cversionNumber)
+	return C.GoString(cversion), int(cversionNumber)
+}
+
+// GetReleaseDate returns the ImageMagick release date.
+func GetReleaseDate() string {
+	return C.GoString(C.MagickGetReleaseDate())
+}
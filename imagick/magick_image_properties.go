@@ -0,0 +1,139 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// GetImageProperties returns every image property (e.g. "exif:*",
+// "xmp:*", "png:*") whose name matches pattern, as a map from property
+// name to value. Use "*" to fetch every property.
+func (mw *MagickWand) GetImageProperties(pattern string) map[string]string {
+	cspattern := C.CString(pattern)
+	defer C.free(unsafe.Pointer(cspattern))
+
+	var count C.size_t
+	cnames := C.MagickGetImageProperties(mw.wand, cspattern, &count)
+	if cnames == nil {
+		return nil
+	}
+	defer relinquishMemory(unsafe.Pointer(cnames))
+
+	properties := make(map[string]string, int(count))
+	for _, cname := range cStringSlice(cnames, int(count)) {
+		name := C.GoString(cname)
+		if value, ok := mw.GetImageProperty(name); ok == nil {
+			properties[name] = value
+		}
+		relinquishMemory(unsafe.Pointer(cname))
+	}
+	return properties
+}
+
+// GetImageProperty returns the value of the named image property, for
+// example "exif:Orientation" or a custom property set with
+// SetImageProperty. It returns an error if the property does not exist.
+func (mw *MagickWand) GetImageProperty(name string) (string, error) {
+	csname := C.CString(name)
+	defer C.free(unsafe.Pointer(csname))
+
+	cvalue := C.MagickGetImageProperty(mw.wand, csname)
+	if cvalue == nil {
+		if err := mw.GetLastError(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("imagick: no such image property %q", name)
+	}
+	defer relinquishMemory(unsafe.Pointer(cvalue))
+	return C.GoString(cvalue), nil
+}
+
+// SetImageProperty sets the named image property to value, creating it
+// if it does not already exist.
+func (mw *MagickWand) SetImageProperty(name, value string) error {
+	csname := C.CString(name)
+	defer C.free(unsafe.Pointer(csname))
+	csvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(csvalue))
+
+	if C.MagickSetImageProperty(mw.wand, csname, csvalue) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// DeleteImageProperty removes the named image property.
+func (mw *MagickWand) DeleteImageProperty(name string) error {
+	csname := C.CString(name)
+	defer C.free(unsafe.Pointer(csname))
+
+	if C.MagickDeleteImageProperty(mw.wand, csname) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// GetImageProfiles returns the names of the embedded image profiles
+// (e.g. "icc", "exif", "xmp") whose name matches pattern. Use "*" to
+// fetch every profile name.
+func (mw *MagickWand) GetImageProfiles(pattern string) []string {
+	cspattern := C.CString(pattern)
+	defer C.free(unsafe.Pointer(cspattern))
+
+	var count C.size_t
+	cnames := C.MagickGetImageProfiles(mw.wand, cspattern, &count)
+	if cnames == nil {
+		return nil
+	}
+	defer relinquishMemory(unsafe.Pointer(cnames))
+
+	names := make([]string, 0, int(count))
+	for _, cname := range cStringSlice(cnames, int(count)) {
+		names = append(names, C.GoString(cname))
+		relinquishMemory(unsafe.Pointer(cname))
+	}
+	return names
+}
+
+// GetImageProfile returns the raw bytes of the named embedded profile
+// (e.g. "icc" for an embedded ICC color profile).
+func (mw *MagickWand) GetImageProfile(name string) ([]byte, error) {
+	csname := C.CString(name)
+	defer C.free(unsafe.Pointer(csname))
+
+	var length C.size_t
+	cprofile := C.MagickGetImageProfile(mw.wand, csname, &length)
+	if cprofile == nil {
+		return nil, mw.GetLastError()
+	}
+	defer relinquishMemory(unsafe.Pointer(cprofile))
+	return C.GoBytes(unsafe.Pointer(cprofile), C.int(length)), nil
+}
+
+// SetImageProfile attaches profile to the image under the given name,
+// replacing any existing profile of that name.
+func (mw *MagickWand) SetImageProfile(name string, profile []byte) error {
+	csname := C.CString(name)
+	defer C.free(unsafe.Pointer(csname))
+
+	var ptr unsafe.Pointer
+	if len(profile) > 0 {
+		ptr = unsafe.Pointer(&profile[0])
+	}
+
+	if C.MagickSetImageProfile(mw.wand, csname, ptr, C.size_t(len(profile))) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetGetDeleteImageProperty(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := mw.SetImageProperty("imagick:test", "round-trip"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	value, err := mw.GetImageProperty("imagick:test")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if value != "round-trip" {
+		t.Fatalf("Expected property value %q; Got %q", "round-trip", value)
+	}
+
+	if err := mw.DeleteImageProperty("imagick:test"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := mw.GetImageProperty("imagick:test"); err == nil {
+		t.Fatal("Expected error after deleting image property")
+	}
+}
+
+func TestGetImageProperties(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := mw.SetImageProperty("imagick:test", "value"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	properties := mw.GetImageProperties("imagick:*")
+	if properties["imagick:test"] != "value" {
+		t.Fatalf("Expected GetImageProperties to include imagick:test=value; Got %v", properties)
+	}
+}
+
+func TestGetImagePropertyExifOrientation(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	blob := jpegWithOrientation(t, 6)
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImageBlob(blob); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	value, err := mw.GetImageProperty("exif:Orientation")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.Contains(value, "6") {
+		t.Fatalf("Expected exif:Orientation to report 6; Got %q", value)
+	}
+
+	properties := mw.GetImageProperties("exif:*")
+	if _, ok := properties["exif:Orientation"]; !ok {
+		t.Fatalf("Expected GetImageProperties(\"exif:*\") to include exif:Orientation; Got %v", properties)
+	}
+}
+
+func TestGetImageProfiles(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	profile := []byte{0x01, 0x02, 0x03, 0x04}
+	if err := mw.SetImageProfile("myProfile", profile); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	names := mw.GetImageProfiles("*")
+	found := false
+	for _, name := range names {
+		if name == "myProfile" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected GetImageProfiles to list myProfile; Got %v", names)
+	}
+
+	got, err := mw.GetImageProfile("myProfile")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(got) != string(profile) {
+		t.Fatalf("Expected profile bytes %v; Got %v", profile, got)
+	}
+}
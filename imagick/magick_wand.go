@@ -0,0 +1,378 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// magickWandCounter tracks the number of live MagickWand instances so
+// tests can assert that every wand created during a run was destroyed.
+var magickWandCounter int64
+
+// MagickWand is a Go wrapper around the ImageMagick MagickWand C API. A
+// MagickWand holds a handle to an ImageMagick "wand", the opaque object
+// ImageMagick uses to represent an image or image sequence plus all of
+// its settings.
+type MagickWand struct {
+	wand *C.MagickWand
+}
+
+// NewMagickWand returns a new, empty MagickWand. The caller must call
+// Destroy when done with it, either explicitly or by relying on the
+// finalizer installed here as a safety net.
+func NewMagickWand() *MagickWand {
+	return newMagickWand(C.NewMagickWand())
+}
+
+// newMagickWand wraps an existing *C.MagickWand, registers it with the
+// live-wand counter and installs a finalizer that releases the
+// underlying C resources if the caller forgets to call Destroy. If
+// cwand is nil (e.g. the ImageMagick call that produced it failed), the
+// returned MagickWand is left unregistered: Destroy already no-ops on a
+// nil wand and never decrements the counter, so counting it here would
+// leak magickWandCounter permanently.
+func newMagickWand(cwand *C.MagickWand) *MagickWand {
+	if cwand == nil {
+		return &MagickWand{}
+	}
+	mw := &MagickWand{wand: cwand}
+	atomic.AddInt64(&magickWandCounter, 1)
+	runtime.SetFinalizer(mw, func(mw *MagickWand) {
+		mw.Destroy()
+	})
+	return mw
+}
+
+// Destroy deallocates the underlying MagickWand and clears the finalizer.
+// It is safe to call Destroy more than once.
+func (mw *MagickWand) Destroy() {
+	if mw.wand == nil {
+		return
+	}
+	mw.wand = C.DestroyMagickWand(mw.wand)
+	atomic.AddInt64(&magickWandCounter, -1)
+	runtime.SetFinalizer(mw, nil)
+}
+
+// Clone returns a deep copy of the wand, including its image sequence and
+// settings.
+func (mw *MagickWand) Clone() *MagickWand {
+	return newMagickWand(C.CloneMagickWand(mw.wand))
+}
+
+// IsVerified returns whether the wand's underlying C object is a valid,
+// non-destroyed MagickWand instance.
+func (mw *MagickWand) IsVerified() bool {
+	return mw.wand != nil && C.IsMagickWand(mw.wand) != C.MagickFalse
+}
+
+// GetLastError returns the error message (if any) raised by the last
+// operation performed against the wand.
+func (mw *MagickWand) GetLastError() error {
+	var ctype C.ExceptionType
+	cmsg := C.MagickGetException(mw.wand, &ctype)
+	defer relinquishMemory(unsafe.Pointer(cmsg))
+	if ctype == C.UndefinedException {
+		return nil
+	}
+	msg := C.GoString(cmsg)
+	C.MagickClearException(mw.wand)
+	return fmt.Errorf("%s", msg)
+}
+
+// ReadImage reads an image or image sequence from the named file, URL or
+// built-in image generator (e.g. "logo:", "rose:").
+func (mw *MagickWand) ReadImage(filename string) error {
+	csfilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(csfilename))
+	if C.MagickReadImage(mw.wand, csfilename) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// ReadImageBlob reads an image or image sequence from an in-memory byte
+// slice.
+func (mw *MagickWand) ReadImageBlob(blob []byte) error {
+	if len(blob) == 0 {
+		return errors.New("imagick: zero length blob not permitted")
+	}
+	if C.MagickReadImageBlob(mw.wand, unsafe.Pointer(&blob[0]), C.size_t(len(blob))) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// GetImageBlob returns the current image encoded in its current format as
+// an in-memory byte slice.
+func (mw *MagickWand) GetImageBlob() []byte {
+	var length C.size_t
+	cblob := C.MagickGetImageBlob(mw.wand, &length)
+	if cblob == nil {
+		return nil
+	}
+	defer relinquishMemory(unsafe.Pointer(cblob))
+	return C.GoBytes(unsafe.Pointer(cblob), C.int(length))
+}
+
+// GetImageWidth returns the width of the current image in pixels.
+func (mw *MagickWand) GetImageWidth() uint {
+	return uint(C.MagickGetImageWidth(mw.wand))
+}
+
+// GetImageHeight returns the height of the current image in pixels.
+func (mw *MagickWand) GetImageHeight() uint {
+	return uint(C.MagickGetImageHeight(mw.wand))
+}
+
+// ScaleImage changes the size of the image to the given dimensions,
+// ignoring the aspect ratio.
+func (mw *MagickWand) ScaleImage(columns, rows uint) error {
+	if C.MagickScaleImage(mw.wand, C.size_t(columns), C.size_t(rows)) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// DeleteImageArtifact deletes the named image artifact, or all artifacts
+// matching pattern if it contains "*" glob characters.
+func (mw *MagickWand) DeleteImageArtifact(artifact string) error {
+	csartifact := C.CString(artifact)
+	defer C.free(unsafe.Pointer(csartifact))
+	if C.MagickDeleteImageArtifact(mw.wand, csartifact) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// QueryConfigureOptions returns the names of configure options that match
+// the given pattern, for example "*" for all options.
+func (mw *MagickWand) QueryConfigureOptions(pattern string) []string {
+	cspattern := C.CString(pattern)
+	defer C.free(unsafe.Pointer(cspattern))
+
+	var count C.size_t
+	coptions := C.MagickQueryConfigureOptions(cspattern, &count)
+	if coptions == nil {
+		return nil
+	}
+	defer relinquishMemory(unsafe.Pointer(coptions))
+
+	options := make([]string, 0, int(count))
+	for _, cstr := range cStringSlice(coptions, int(count)) {
+		options = append(options, C.GoString(cstr))
+		relinquishMemory(unsafe.Pointer(cstr))
+	}
+	return options
+}
+
+// QueryConfigureOption returns the value of the named configure option.
+func (mw *MagickWand) QueryConfigureOption(option string) (string, error) {
+	csoption := C.CString(option)
+	defer C.free(unsafe.Pointer(csoption))
+
+	cvalue := C.MagickQueryConfigureOption(csoption)
+	if cvalue == nil {
+		return "", fmt.Errorf("imagick: no such configure option %q", option)
+	}
+	defer relinquishMemory(unsafe.Pointer(cvalue))
+	return C.GoString(cvalue), nil
+}
+
+// QueryFonts returns the names of the fonts available to ImageMagick that
+// match pattern.
+func (mw *MagickWand) QueryFonts(pattern string) []string {
+	cspattern := C.CString(pattern)
+	defer C.free(unsafe.Pointer(cspattern))
+
+	var count C.size_t
+	cfonts := C.MagickQueryFonts(cspattern, &count)
+	if cfonts == nil {
+		return nil
+	}
+	defer relinquishMemory(unsafe.Pointer(cfonts))
+
+	fonts := make([]string, 0, int(count))
+	for _, cstr := range cStringSlice(cfonts, int(count)) {
+		fonts = append(fonts, C.GoString(cstr))
+		relinquishMemory(unsafe.Pointer(cstr))
+	}
+	return fonts
+}
+
+// QueryFormats returns the names of the image formats available to
+// ImageMagick that match pattern.
+func (mw *MagickWand) QueryFormats(pattern string) []string {
+	cspattern := C.CString(pattern)
+	defer C.free(unsafe.Pointer(cspattern))
+
+	var count C.size_t
+	cformats := C.MagickQueryFormats(cspattern, &count)
+	if cformats == nil {
+		return nil
+	}
+	defer relinquishMemory(unsafe.Pointer(cformats))
+
+	formats := make([]string, 0, int(count))
+	for _, cstr := range cStringSlice(cformats, int(count)) {
+		formats = append(formats, C.GoString(cstr))
+		relinquishMemory(unsafe.Pointer(cstr))
+	}
+	return formats
+}
+
+// ExportImagePixels extracts the pixel data for the rectangle (x, y,
+// width, height) from the current image, in the channel order described
+// by m (e.g. "RGB", "RGBA", "R") and the given storage type. The
+// returned value is a freshly allocated slice whose element type matches
+// storage (e.g. []float32 for PIXEL_FLOAT).
+func (mw *MagickWand) ExportImagePixels(x, y int, width, height uint, m string, storage StorageType) (interface{}, error) {
+	csmap := C.CString(m)
+	defer C.free(unsafe.Pointer(csmap))
+
+	count := int(width) * int(height) * len(m)
+	pixels, ptr, err := allocatePixelBuffer(storage, count)
+	if err != nil {
+		return nil, err
+	}
+
+	ok := C.MagickExportImagePixels(mw.wand,
+		C.ssize_t(x), C.ssize_t(y), C.size_t(width), C.size_t(height),
+		csmap, C.StorageType(storage), ptr)
+	if ok == C.MagickFalse {
+		return nil, mw.GetLastError()
+	}
+	return pixels, nil
+}
+
+// ImportImagePixels replaces the pixel data for the rectangle (x, y,
+// width, height) of the current image with pixels, which must be one of
+// the slice types accepted by pixelInterfaceToPtr.
+func (mw *MagickWand) ImportImagePixels(x, y int, width, height uint, m string, storage StorageType, pixels interface{}) error {
+	csmap := C.CString(m)
+	defer C.free(unsafe.Pointer(csmap))
+
+	ptr, gotStorage, err := pixelInterfaceToPtr(pixels)
+	if err != nil {
+		return err
+	}
+	if storage == PIXEL_UNDEFINED {
+		storage = gotStorage
+	}
+
+	if C.MagickImportImagePixels(mw.wand,
+		C.ssize_t(x), C.ssize_t(y), C.size_t(width), C.size_t(height),
+		csmap, C.StorageType(storage), ptr) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// allocatePixelBuffer allocates a Go slice of count elements whose type
+// matches storage and returns it alongside an unsafe.Pointer to its
+// backing array suitable for passing to the C pixel export APIs.
+func allocatePixelBuffer(storage StorageType, count int) (interface{}, unsafe.Pointer, error) {
+	switch storage {
+	case PIXEL_CHAR:
+		buf := make([]byte, count)
+		return buf, unsafe.Pointer(&buf[0]), nil
+	case PIXEL_SHORT:
+		buf := make([]int16, count)
+		return buf, unsafe.Pointer(&buf[0]), nil
+	case PIXEL_INTEGER:
+		buf := make([]int32, count)
+		return buf, unsafe.Pointer(&buf[0]), nil
+	case PIXEL_LONG:
+		buf := make([]int64, count)
+		return buf, unsafe.Pointer(&buf[0]), nil
+	case PIXEL_FLOAT:
+		buf := make([]float32, count)
+		return buf, unsafe.Pointer(&buf[0]), nil
+	case PIXEL_DOUBLE:
+		buf := make([]float64, count)
+		return buf, unsafe.Pointer(&buf[0]), nil
+	default:
+		return nil, nil, fmt.Errorf("imagick: unsupported storage type %v", storage)
+	}
+}
+
+// pixelInterfaceToPtr returns an unsafe.Pointer to the backing array of
+// pixels, along with the StorageType it corresponds to. pixels must be
+// one of []byte, []int16, []int32, []int64, []float32 or []float64.
+func pixelInterfaceToPtr(pixels interface{}) (unsafe.Pointer, StorageType, error) {
+	switch p := pixels.(type) {
+	case []byte:
+		if len(p) == 0 {
+			return nil, PIXEL_CHAR, errors.New("imagick: empty pixel slice")
+		}
+		return unsafe.Pointer(&p[0]), PIXEL_CHAR, nil
+	case []int16:
+		if len(p) == 0 {
+			return nil, PIXEL_SHORT, errors.New("imagick: empty pixel slice")
+		}
+		return unsafe.Pointer(&p[0]), PIXEL_SHORT, nil
+	case []int32:
+		if len(p) == 0 {
+			return nil, PIXEL_INTEGER, errors.New("imagick: empty pixel slice")
+		}
+		return unsafe.Pointer(&p[0]), PIXEL_INTEGER, nil
+	case []int64:
+		if len(p) == 0 {
+			return nil, PIXEL_LONG, errors.New("imagick: empty pixel slice")
+		}
+		return unsafe.Pointer(&p[0]), PIXEL_LONG, nil
+	case []float32:
+		if len(p) == 0 {
+			return nil, PIXEL_FLOAT, errors.New("imagick: empty pixel slice")
+		}
+		return unsafe.Pointer(&p[0]), PIXEL_FLOAT, nil
+	case []float64:
+		if len(p) == 0 {
+			return nil, PIXEL_DOUBLE, errors.New("imagick: empty pixel slice")
+		}
+		return unsafe.Pointer(&p[0]), PIXEL_DOUBLE, nil
+	default:
+		return nil, PIXEL_UNDEFINED, fmt.Errorf("imagick: unsupported pixel slice type %s", reflect.TypeOf(pixels))
+	}
+}
+
+// relinquishMemory releases memory that ImageMagick allocated and handed
+// back to Go (property lists, blobs, query results, ...). Every such
+// pointer must be passed through here instead of C.free to avoid
+// allocator mismatches.
+func relinquishMemory(ptr unsafe.Pointer) {
+	if ptr != nil {
+		C.MagickRelinquishMemory(ptr)
+	}
+}
+
+// cStringSlice turns a NULL-less C array of count char* into a Go slice
+// of *C.char for iteration. It does not copy or free the strings.
+func cStringSlice(arr **C.char, count int) []*C.char {
+	return (*[1 << 28]*C.char)(unsafe.Pointer(arr))[:count:count]
+}
+
+// isImageMagickCleaned reports whether every wand, drawing wand, pixel
+// iterator and pixel wand created during the process lifetime has been
+// destroyed. It is used by tests to catch resource leaks.
+func isImageMagickCleaned() bool {
+	return atomic.LoadInt64(&magickWandCounter) == 0 &&
+		atomic.LoadInt64(&drawingWandCounter) == 0 &&
+		atomic.LoadInt64(&pixelIteratorCounter) == 0 &&
+		atomic.LoadInt64(&pixelWandCounter) == 0
+}
@@ -0,0 +1,95 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+#include <stdlib.h>
+*/
+import "C"
+
+// DecodeOptions controls how ReadImageWithOptions and
+// ReadImageBlobWithOptions decode an image, trading the current
+// read-then-AutoOrient-then-resize dance (which decodes at full
+// resolution before scaling down) for hints applied before the decode
+// itself.
+type DecodeOptions struct {
+	// AutoOrient rotates the image according to its EXIF Orientation
+	// tag (if any) and resets the tag to "top-left" once applied, so
+	// callers never have to think about it again.
+	AutoOrient bool
+
+	// MaxWidth and MaxHeight, when non-zero, are passed to
+	// MagickSetSize before decoding so that formats with a fast
+	// pre-decode downscale (notably JPEG's DCT scaling) never
+	// materialize the full-resolution image in memory.
+	MaxWidth, MaxHeight uint
+
+	// SwapDimensions is set by ReadImageWithOptions/
+	// ReadImageBlobWithOptions to true when AutoOrient applied a 90 or
+	// 270 degree rotation, telling the caller that GetImageWidth/
+	// GetImageHeight are already reporting the post-rotation axes.
+	SwapDimensions bool
+}
+
+// ReadImageWithOptions reads the image at filename into mw, applying
+// opts before and after the decode. It replaces the common three-step
+// "ReadImage then AutoOrientImage then ScaleImage" pattern, which wastes
+// memory decoding huge camera JPEGs at full resolution before resizing.
+func (mw *MagickWand) ReadImageWithOptions(filename string, opts *DecodeOptions) error {
+	if err := mw.applySizeHint(opts); err != nil {
+		return err
+	}
+	if err := mw.ReadImage(filename); err != nil {
+		return err
+	}
+	return mw.applyAutoOrient(opts)
+}
+
+// ReadImageBlobWithOptions reads the image encoded in blob into mw,
+// applying opts before and after the decode.
+func (mw *MagickWand) ReadImageBlobWithOptions(blob []byte, opts *DecodeOptions) error {
+	if err := mw.applySizeHint(opts); err != nil {
+		return err
+	}
+	if err := mw.ReadImageBlob(blob); err != nil {
+		return err
+	}
+	return mw.applyAutoOrient(opts)
+}
+
+// applySizeHint passes opts.MaxWidth/MaxHeight to MagickSetSize so
+// formats that support a cheap pre-decode downscale (JPEG via libjpeg's
+// DCT scaling) do not decode at full resolution first.
+func (mw *MagickWand) applySizeHint(opts *DecodeOptions) error {
+	if opts == nil || (opts.MaxWidth == 0 && opts.MaxHeight == 0) {
+		return nil
+	}
+	if C.MagickSetSize(mw.wand, C.size_t(opts.MaxWidth), C.size_t(opts.MaxHeight)) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// applyAutoOrient rotates the just-decoded image according to its EXIF
+// orientation when requested, recording in opts whether the rotation
+// swapped width and height.
+func (mw *MagickWand) applyAutoOrient(opts *DecodeOptions) error {
+	if opts == nil || !opts.AutoOrient {
+		return nil
+	}
+
+	orientation := OrientationType(C.MagickGetImageOrientation(mw.wand))
+	opts.SwapDimensions = orientation.needsDimensionSwap()
+
+	if C.MagickAutoOrientImage(mw.wand) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	if C.MagickSetImageOrientation(mw.wand, C.OrientationType(ORIENTATION_TOPLEFT)) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
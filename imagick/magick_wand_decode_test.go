@@ -0,0 +1,95 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"testing"
+)
+
+func TestReadImageWithOptionsMaxSize(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	opts := &DecodeOptions{MaxWidth: 64, MaxHeight: 64}
+	if err := mw.ReadImageWithOptions(`logo:`, opts); err != nil {
+		t.Fatal(err.Error())
+	}
+	if mw.GetImageWidth() == 0 || mw.GetImageHeight() == 0 {
+		t.Fatal("Expected a decoded image with non-zero dimensions")
+	}
+}
+
+func TestReadImageWithOptionsAutoOrient(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	opts := &DecodeOptions{AutoOrient: true}
+	if err := mw.ReadImageWithOptions(`logo:`, opts); err != nil {
+		t.Fatal(err.Error())
+	}
+	// The built-in "logo:" image carries no EXIF Orientation tag, so
+	// no rotation should have been reported.
+	if opts.SwapDimensions {
+		t.Fatal("Expected SwapDimensions to be false for an unoriented image")
+	}
+}
+
+func TestReadImageBlobWithOptionsAutoOrientSwapsDimensions(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	// Orientation 6 ("rotate 90 CW") is one of the four EXIF values
+	// that trade width and height once applied.
+	blob := jpegWithOrientation(t, 6)
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	opts := &DecodeOptions{AutoOrient: true}
+	if err := mw.ReadImageBlobWithOptions(blob, opts); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !opts.SwapDimensions {
+		t.Fatal("Expected SwapDimensions to be true for a 90-degree EXIF orientation")
+	}
+}
+
+func TestReadImageBlobWithOptions(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	src := NewMagickWand()
+	defer src.Destroy()
+	if err := src.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+	blob := src.GetImageBlob()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	opts := &DecodeOptions{AutoOrient: true}
+	if err := mw.ReadImageBlobWithOptions(blob, opts); err != nil {
+		t.Fatal(err.Error())
+	}
+}
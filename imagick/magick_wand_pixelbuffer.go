@@ -0,0 +1,112 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// bytesPerComponent returns the size, in bytes, of a single pixel
+// component stored as storage.
+func bytesPerComponent(storage StorageType) (int, error) {
+	switch storage {
+	case PIXEL_CHAR:
+		return 1, nil
+	case PIXEL_SHORT:
+		return 2, nil
+	case PIXEL_INTEGER, PIXEL_FLOAT:
+		return 4, nil
+	case PIXEL_LONG, PIXEL_DOUBLE:
+		return 8, nil
+	default:
+		return 0, fmt.Errorf("imagick: unsupported storage type %v", storage)
+	}
+}
+
+// PixelBufferSize returns the number of bytes a caller must allocate to
+// hold a w x h pixel rectangle with the given channel map (e.g. "RGB",
+// "RGBA") and storage type, for use with ExportImagePixelsInto and
+// ImportImagePixelsFrom.
+func PixelBufferSize(w, h int, m string, storage StorageType) (int, error) {
+	componentSize, err := bytesPerComponent(storage)
+	if err != nil {
+		return 0, err
+	}
+	return w * h * len(m) * componentSize, nil
+}
+
+// ExportImagePixelsInto extracts the pixel data for the rectangle (x, y,
+// w, h) into dst, which the caller must have sized with PixelBufferSize.
+// Unlike ExportImagePixels, it never allocates: the pixel bytes are
+// written directly into dst's backing array, so calling it repeatedly
+// with the same buffer (e.g. once per video frame) does not churn the
+// garbage collector the way BenchmarkExportImagePixels shows the
+// allocating form does.
+func (mw *MagickWand) ExportImagePixelsInto(x, y, w, h int, m string, storage StorageType, dst []byte) error {
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("imagick: invalid pixel rectangle %dx%d: width and height must be positive", w, h)
+	}
+	if x < 0 || y < 0 {
+		return fmt.Errorf("imagick: invalid pixel rectangle origin (%d, %d): must be non-negative", x, y)
+	}
+
+	needed, err := PixelBufferSize(w, h, m, storage)
+	if err != nil {
+		return err
+	}
+	if len(dst) < needed {
+		return fmt.Errorf("imagick: dst too small for %dx%d %q pixels: need %d bytes, got %d", w, h, m, needed, len(dst))
+	}
+
+	csmap := C.CString(m)
+	defer C.free(unsafe.Pointer(csmap))
+
+	if C.MagickExportImagePixels(mw.wand,
+		C.ssize_t(x), C.ssize_t(y), C.size_t(w), C.size_t(h),
+		csmap, C.StorageType(storage), unsafe.Pointer(&dst[0])) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// ImportImagePixelsFrom replaces the pixel data for the rectangle (x, y,
+// w, h) of the current image with the raw bytes in src, which must have
+// been packed the way PixelBufferSize/ExportImagePixelsInto describe.
+// Like ExportImagePixelsInto, it reads src's backing array directly
+// without an intermediate typed slice allocation.
+func (mw *MagickWand) ImportImagePixelsFrom(x, y, w, h int, m string, storage StorageType, src []byte) error {
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("imagick: invalid pixel rectangle %dx%d: width and height must be positive", w, h)
+	}
+	if x < 0 || y < 0 {
+		return fmt.Errorf("imagick: invalid pixel rectangle origin (%d, %d): must be non-negative", x, y)
+	}
+
+	needed, err := PixelBufferSize(w, h, m, storage)
+	if err != nil {
+		return err
+	}
+	if len(src) < needed {
+		return fmt.Errorf("imagick: src too small for %dx%d %q pixels: need %d bytes, got %d", w, h, m, needed, len(src))
+	}
+
+	csmap := C.CString(m)
+	defer C.free(unsafe.Pointer(csmap))
+
+	if C.MagickImportImagePixels(mw.wand,
+		C.ssize_t(x), C.ssize_t(y), C.size_t(w), C.size_t(h),
+		csmap, C.StorageType(storage), unsafe.Pointer(&src[0])) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
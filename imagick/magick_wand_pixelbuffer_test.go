@@ -0,0 +1,140 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"testing"
+)
+
+func TestExportImportImagePixelsInto(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+	width, height := int(mw.GetImageWidth()), int(mw.GetImageHeight())
+
+	size, err := PixelBufferSize(width, height, "RGB", PIXEL_CHAR)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	buf := make([]byte, size)
+	if err := mw.ExportImagePixelsInto(0, 0, width, height, "RGB", PIXEL_CHAR, buf); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := mw.ImportImagePixelsFrom(0, 0, width, height, "RGB", PIXEL_CHAR, buf); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := PixelBufferSize(width, height, "RGB", PIXEL_CHAR); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tooSmall := make([]byte, size-1)
+	if err := mw.ExportImagePixelsInto(0, 0, width, height, "RGB", PIXEL_CHAR, tooSmall); err == nil {
+		t.Fatal("Expected error when dst is too small")
+	}
+}
+
+func TestExportImportImagePixelsIntoRejectsInvalidRect(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	buf := make([]byte, 0)
+	if err := mw.ExportImagePixelsInto(0, 0, 0, 0, "RGB", PIXEL_CHAR, buf); err == nil {
+		t.Fatal("Expected error for zero-sized rectangle")
+	}
+	if err := mw.ExportImagePixelsInto(0, 0, -1, 10, "RGB", PIXEL_CHAR, buf); err == nil {
+		t.Fatal("Expected error for negative width")
+	}
+	if err := mw.ExportImagePixelsInto(0, 0, 10, -1, "RGB", PIXEL_CHAR, buf); err == nil {
+		t.Fatal("Expected error for negative height")
+	}
+	if err := mw.ExportImagePixelsInto(-1, 0, 10, 10, "RGB", PIXEL_CHAR, buf); err == nil {
+		t.Fatal("Expected error for negative x")
+	}
+	if err := mw.ExportImagePixelsInto(0, -1, 10, 10, "RGB", PIXEL_CHAR, buf); err == nil {
+		t.Fatal("Expected error for negative y")
+	}
+
+	if err := mw.ImportImagePixelsFrom(0, 0, 0, 0, "RGB", PIXEL_CHAR, buf); err == nil {
+		t.Fatal("Expected error for zero-sized rectangle")
+	}
+	if err := mw.ImportImagePixelsFrom(0, 0, -1, 10, "RGB", PIXEL_CHAR, buf); err == nil {
+		t.Fatal("Expected error for negative width")
+	}
+}
+
+func BenchmarkExportImagePixelsInto(b *testing.B) {
+	wand := NewMagickWand()
+	defer wand.Destroy()
+
+	wand.ReadImage("logo:")
+	wand.ScaleImage(1024, 1024)
+
+	size, err := PixelBufferSize(1024, 1024, "RGB", PIXEL_FLOAT)
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+	buf := make([]byte, size)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := wand.ExportImagePixelsInto(0, 0, 1024, 1024, "RGB", PIXEL_FLOAT, buf); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+
+	b.StopTimer()
+}
+
+func BenchmarkImportImagePixelsFrom(b *testing.B) {
+	wand := NewMagickWand()
+	defer wand.Destroy()
+
+	wand.ReadImage("logo:")
+	wand.ScaleImage(1024, 1024)
+
+	size, err := PixelBufferSize(1024, 1024, "RGB", PIXEL_FLOAT)
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+	buf := make([]byte, size)
+	if err := wand.ExportImagePixelsInto(0, 0, 1024, 1024, "RGB", PIXEL_FLOAT, buf); err != nil {
+		b.Fatal(err.Error())
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := wand.ImportImagePixelsFrom(0, 0, 1024, 1024, "RGB", PIXEL_FLOAT, buf); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+
+	b.StopTimer()
+}
@@ -0,0 +1,199 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"unsafe"
+)
+
+// StreamOptions controls how ReadImageStream decodes a multi-frame
+// sequence (animated GIF/APNG, multi-page TIFF, ...).
+type StreamOptions struct {
+	// Format, when non-empty, is set on the wand before decoding so
+	// ImageMagick does not have to sniff the container (e.g. "GIF").
+	Format string
+
+	// MaxFrames limits how many frames are kept after decoding. Zero
+	// means keep every frame.
+	MaxFrames int
+
+	// Coalesce requests that each frame be expanded to the full
+	// canvas size with disposal already applied, matching what a
+	// naive "draw every frame in order" consumer expects. Without it,
+	// callers must honor Frame.Dispose and Frame.OffsetX/OffsetY
+	// themselves, same as image/gif.
+	Coalesce bool
+}
+
+// Frame describes a single image in a sequence, along with the animation
+// metadata a caller needs to play it back correctly.
+type Frame struct {
+	// Wand holds a copy of only this frame's image. ForEachFrame
+	// destroys it as soon as the callback returns.
+	Wand *MagickWand
+
+	// Index is the frame's position in the original sequence.
+	Index int
+
+	// Delay is the frame's display time in 1/100ths of a second, as
+	// stored in the image format (e.g. GIF).
+	Delay uint
+
+	// Dispose describes how the frame should be treated before the
+	// next one is composited.
+	Dispose DisposeType
+
+	// OffsetX and OffsetY are the frame's page offset within the
+	// logical canvas.
+	OffsetX, OffsetY int
+}
+
+// ReadImageStream reads a (possibly multi-frame) image sequence from r
+// into mw, honoring opts. The whole of r is read into memory and every
+// frame is decoded before opts.MaxFrames drops the frames beyond the
+// limit, so MaxFrames bounds what mw keeps, not the peak memory of the
+// decode.
+func (mw *MagickWand) ReadImageStream(r io.Reader, opts *StreamOptions) error {
+	blob, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if opts != nil && opts.Format != "" {
+		if err := mw.SetFormat(opts.Format); err != nil {
+			return err
+		}
+	}
+
+	if err := mw.ReadImageBlob(blob); err != nil {
+		return err
+	}
+
+	if opts != nil && opts.MaxFrames > 0 {
+		if err := mw.truncateFrames(opts.MaxFrames); err != nil {
+			return err
+		}
+	}
+
+	if opts != nil && opts.Coalesce {
+		coalesced := C.MagickCoalesceImages(mw.wand)
+		if coalesced == nil {
+			return mw.GetLastError()
+		}
+		old := mw.wand
+		mw.wand = coalesced
+		C.DestroyMagickWand(old)
+	}
+
+	return nil
+}
+
+// truncateFrames removes every frame beyond the first max frames of the
+// wand's image sequence.
+func (mw *MagickWand) truncateFrames(max int) error {
+	total := int(C.MagickGetNumberImages(mw.wand))
+	for i := total - 1; i >= max; i-- {
+		if C.MagickSetIteratorIndex(mw.wand, C.ssize_t(i)) == C.MagickFalse {
+			return mw.GetLastError()
+		}
+		if C.MagickRemoveImage(mw.wand) == C.MagickFalse {
+			return mw.GetLastError()
+		}
+	}
+	return nil
+}
+
+// SetFormat sets the format used to interpret subsequent reads and
+// writes performed on the wand as a whole (as opposed to the current
+// image only).
+func (mw *MagickWand) SetFormat(format string) error {
+	csformat := C.CString(format)
+	defer C.free(unsafe.Pointer(csformat))
+	if C.MagickSetFormat(mw.wand, csformat) == C.MagickFalse {
+		return mw.GetLastError()
+	}
+	return nil
+}
+
+// WriteImageStream encodes mw's full image sequence in format and writes
+// it to w.
+func (mw *MagickWand) WriteImageStream(w io.Writer, format string) error {
+	if format != "" {
+		if err := mw.SetFormat(format); err != nil {
+			return err
+		}
+	}
+
+	var length C.size_t
+	cblob := C.MagickGetImagesBlob(mw.wand, &length)
+	if cblob == nil {
+		return mw.GetLastError()
+	}
+	defer relinquishMemory(unsafe.Pointer(cblob))
+
+	blob := C.GoBytes(unsafe.Pointer(cblob), C.int(length))
+	_, err := w.Write(blob)
+	return err
+}
+
+// ForEachFrame calls fn once per frame of mw's image sequence, in order,
+// passing a single-frame MagickWand and the frame's animation metadata.
+// frame.Wand is a copy of the frame and is destroyed automatically after
+// fn returns. ForEachFrame does not remove frames from mw: the whole
+// decoded sequence stays in mw throughout. Iteration stops at the first
+// error returned by fn.
+func (mw *MagickWand) ForEachFrame(fn func(frame *Frame, idx int) error) error {
+	total := int(C.MagickGetNumberImages(mw.wand))
+
+	for i := 0; i < total; i++ {
+		if C.MagickSetIteratorIndex(mw.wand, C.ssize_t(i)) == C.MagickFalse {
+			return mw.GetLastError()
+		}
+
+		frame, err := mw.currentFrame(i)
+		if err != nil {
+			return err
+		}
+
+		err = fn(frame, i)
+		frame.Wand.Destroy()
+		if err != nil {
+			return fmt.Errorf("imagick: frame %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// currentFrame snapshots the wand's currently-selected image, along with
+// its delay/disposal/offset metadata, into a standalone Frame.
+func (mw *MagickWand) currentFrame(index int) (*Frame, error) {
+	frameWand := newMagickWand(C.MagickGetImage(mw.wand))
+	if !frameWand.IsVerified() {
+		return nil, mw.GetLastError()
+	}
+
+	var width, height C.size_t
+	var offsetX, offsetY C.ssize_t
+	C.MagickGetImagePage(mw.wand, &width, &height, &offsetX, &offsetY)
+
+	return &Frame{
+		Wand:    frameWand,
+		Index:   index,
+		Delay:   uint(C.MagickGetImageDelay(mw.wand)),
+		Dispose: DisposeType(C.MagickGetImageDispose(mw.wand)),
+		OffsetX: int(offsetX),
+		OffsetY: int(offsetY),
+	}, nil
+}
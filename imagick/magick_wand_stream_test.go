@@ -0,0 +1,188 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+// animatedGIF builds an in-memory 3-frame animated GIF fixture: an 8x8
+// canvas, with the middle frame a smaller sub-image placed at a non-zero
+// offset, the way real animated GIFs only redraw the part of the canvas
+// that changed. That lets tests exercise Frame.OffsetX/OffsetY and
+// StreamOptions.Coalesce against something other than a single-frame
+// built-in image.
+func animatedGIF(t *testing.T) []byte {
+	t.Helper()
+
+	palette := color.Palette{color.White, color.Black, color.RGBA{R: 200, G: 0, B: 0, A: 255}}
+
+	full := func(c color.Color) *image.Paletted {
+		img := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				img.Set(x, y, c)
+			}
+		}
+		return img
+	}
+
+	sub := image.NewPaletted(image.Rect(2, 2, 6, 6), palette)
+	for y := 2; y < 6; y++ {
+		for x := 2; x < 6; x++ {
+			sub.Set(x, y, palette[2])
+		}
+	}
+
+	g := &gif.GIF{
+		Image:    []*image.Paletted{full(palette[0]), sub, full(palette[1])},
+		Delay:    []int{20, 35, 50},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalNone, gif.DisposalNone},
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err.Error())
+	}
+	return buf.Bytes()
+}
+
+func TestReadWriteImageStream(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+	blob := mw.GetImageBlob()
+
+	streamed := NewMagickWand()
+	defer streamed.Destroy()
+
+	if err := streamed.ReadImageStream(bytes.NewReader(blob), nil); err != nil {
+		t.Fatal(err.Error())
+	}
+	if streamed.GetImageWidth() != mw.GetImageWidth() {
+		t.Fatalf("Expected streamed width %d; Got %d", mw.GetImageWidth(), streamed.GetImageWidth())
+	}
+
+	var out bytes.Buffer
+	if err := streamed.WriteImageStream(&out, "MIFF"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if out.Len() == 0 {
+		t.Fatal("WriteImageStream wrote zero bytes")
+	}
+}
+
+func TestForEachFrame(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	if err := mw.ReadImageStream(bytes.NewReader(animatedGIF(t)), nil); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	wantDelay := []uint{20, 35, 50}
+
+	seen := 0
+	err := mw.ForEachFrame(func(frame *Frame, idx int) error {
+		if frame.Wand == nil || !frame.Wand.IsVerified() {
+			t.Fatal("Expected frame.Wand to be a verified MagickWand")
+		}
+		if frame.Index != idx {
+			t.Fatalf("Expected frame.Index %d; Got %d", idx, frame.Index)
+		}
+		if frame.Delay != wantDelay[idx] {
+			t.Fatalf("Frame %d: expected delay %d; Got %d", idx, wantDelay[idx], frame.Delay)
+		}
+		if idx == 1 {
+			if frame.OffsetX != 2 || frame.OffsetY != 2 {
+				t.Fatalf("Frame 1: expected offset (2, 2); Got (%d, %d)", frame.OffsetX, frame.OffsetY)
+			}
+			if frame.Wand.GetImageWidth() != 4 || frame.Wand.GetImageHeight() != 4 {
+				t.Fatalf("Frame 1: expected a 4x4 sub-frame; Got %dx%d", frame.Wand.GetImageWidth(), frame.Wand.GetImageHeight())
+			}
+		}
+		seen++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if seen != 3 {
+		t.Fatalf("Expected ForEachFrame to visit 3 frames; Got %d", seen)
+	}
+}
+
+func TestReadImageStreamMaxFrames(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	limited := NewMagickWand()
+	defer limited.Destroy()
+
+	opts := &StreamOptions{MaxFrames: 2}
+	if err := limited.ReadImageStream(bytes.NewReader(animatedGIF(t)), opts); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	seen := 0
+	if err := limited.ForEachFrame(func(frame *Frame, idx int) error {
+		seen++
+		return nil
+	}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if seen != 2 {
+		t.Fatalf("Expected MaxFrames to truncate the sequence to 2 frames; Got %d", seen)
+	}
+}
+
+func TestReadImageStreamCoalesce(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	mw := NewMagickWand()
+	defer mw.Destroy()
+
+	opts := &StreamOptions{Coalesce: true}
+	if err := mw.ReadImageStream(bytes.NewReader(animatedGIF(t)), opts); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	err := mw.ForEachFrame(func(frame *Frame, idx int) error {
+		if frame.Wand.GetImageWidth() != 8 || frame.Wand.GetImageHeight() != 8 {
+			t.Fatalf("Frame %d: expected Coalesce to expand every frame to the 8x8 canvas; Got %dx%d",
+				idx, frame.Wand.GetImageWidth(), frame.Wand.GetImageHeight())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+*/
+import "C"
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// pixelIteratorCounter tracks the number of live PixelIterator instances
+// so tests can assert that every pixel iterator created during a run was
+// destroyed.
+var pixelIteratorCounter int64
+
+// PixelIterator is a Go wrapper around the ImageMagick PixelIterator C
+// API, used to iterate over or modify the individual pixels of an image
+// one row at a time.
+type PixelIterator struct {
+	iterator *C.PixelIterator
+}
+
+// NewPixelIterator returns a new PixelIterator positioned at the first
+// row of mw's current image.
+func NewPixelIterator(mw *MagickWand) *PixelIterator {
+	pi := &PixelIterator{iterator: C.NewPixelIterator(mw.wand)}
+	atomic.AddInt64(&pixelIteratorCounter, 1)
+	runtime.SetFinalizer(pi, func(pi *PixelIterator) {
+		pi.Destroy()
+	})
+	return pi
+}
+
+// Destroy deallocates the underlying PixelIterator. It is safe to call
+// Destroy more than once.
+func (pi *PixelIterator) Destroy() {
+	if pi.iterator == nil {
+		return
+	}
+	pi.iterator = C.DestroyPixelIterator(pi.iterator)
+	atomic.AddInt64(&pixelIteratorCounter, -1)
+	runtime.SetFinalizer(pi, nil)
+}
+
+// IsVerified returns whether the iterator's underlying C object is a
+// valid, non-destroyed PixelIterator instance.
+func (pi *PixelIterator) IsVerified() bool {
+	return pi.iterator != nil && C.IsPixelIterator(pi.iterator) != C.MagickFalse
+}
@@ -0,0 +1,53 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+*/
+import "C"
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// pixelWandCounter tracks the number of live PixelWand instances so tests
+// can assert that every pixel wand created during a run was destroyed.
+var pixelWandCounter int64
+
+// PixelWand is a Go wrapper around the ImageMagick PixelWand C API, used
+// to describe a single color (e.g. for fill or stroke settings).
+type PixelWand struct {
+	wand *C.PixelWand
+}
+
+// NewPixelWand returns a new PixelWand initialized to opaque black.
+func NewPixelWand() *PixelWand {
+	pw := &PixelWand{wand: C.NewPixelWand()}
+	atomic.AddInt64(&pixelWandCounter, 1)
+	runtime.SetFinalizer(pw, func(pw *PixelWand) {
+		pw.Destroy()
+	})
+	return pw
+}
+
+// Destroy deallocates the underlying PixelWand. It is safe to call
+// Destroy more than once.
+func (pw *PixelWand) Destroy() {
+	if pw.wand == nil {
+		return
+	}
+	pw.wand = C.DestroyPixelWand(pw.wand)
+	atomic.AddInt64(&pixelWandCounter, -1)
+	runtime.SetFinalizer(pw, nil)
+}
+
+// IsVerified returns whether the wand's underlying C object is a valid,
+// non-destroyed PixelWand instance.
+func (pw *PixelWand) IsVerified() bool {
+	return pw.wand != nil && C.IsPixelWand(pw.wand) != C.MagickFalse
+}
@@ -0,0 +1,127 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+/*
+#cgo pkg-config: MagickWand
+#include <wand/MagickWand.h>
+*/
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// PoolOptions configures a WandPool.
+type PoolOptions struct {
+	// MaxConcurrency bounds the number of in-flight cgo calls into
+	// ImageMagick at any one time. ImageMagick's own OpenMP worker
+	// threads and Go's GOMAXPROCS goroutines otherwise routinely
+	// oversubscribe the CPU under concurrent load. Zero means
+	// unlimited.
+	MaxConcurrency int
+
+	// MaxIdle bounds how many unused wands the pool keeps around for
+	// reuse; Put beyond this destroys the wand instead of pooling it.
+	// Zero means unlimited.
+	MaxIdle int
+
+	// ResetOnPut clears a wand's image sequence and settings before
+	// it is returned to the pool, so a wand handed out by Get never
+	// carries state left over from whatever the previous caller did
+	// with it.
+	ResetOnPut bool
+}
+
+// WandPool is a pool of reusable *MagickWand values, for services (e.g.
+// an HTTP image endpoint) that would otherwise pay for a NewMagickWand/
+// Destroy pair on every request. It also optionally caps the number of
+// wands in active use at once, which doubles as a cap on concurrent cgo
+// calls into ImageMagick.
+type WandPool struct {
+	opts PoolOptions
+	pool sync.Pool
+	sem  chan struct{}
+
+	idle int64
+}
+
+// NewWandPool returns a WandPool configured by opts.
+func NewWandPool(opts PoolOptions) *WandPool {
+	wp := &WandPool{opts: opts}
+	wp.pool.New = func() interface{} {
+		return NewMagickWand()
+	}
+	if opts.MaxConcurrency > 0 {
+		wp.sem = make(chan struct{}, opts.MaxConcurrency)
+	}
+	return wp
+}
+
+// Get returns a *MagickWand, reusing one from the pool when available
+// and otherwise allocating a new one via NewMagickWand. If
+// MaxConcurrency is set, Get blocks until a slot is free.
+func (wp *WandPool) Get() *MagickWand {
+	if wp.sem != nil {
+		wp.sem <- struct{}{}
+	}
+	mw := wp.pool.Get().(*MagickWand)
+	decrementIfPositive(&wp.idle)
+	return mw
+}
+
+// Put returns mw to the pool for reuse by a future Get. If ResetOnPut is
+// set, mw's image sequence and settings are cleared first. If MaxIdle is
+// set and the pool already holds that many idle wands, mw is destroyed
+// instead of pooled. Put releases the MaxConcurrency slot mw was
+// acquired under, regardless of which path it takes, so every Get must
+// be matched by exactly one Put.
+func (wp *WandPool) Put(mw *MagickWand) {
+	defer func() {
+		if wp.sem != nil {
+			<-wp.sem
+		}
+	}()
+
+	if wp.opts.ResetOnPut {
+		resetMagickWand(mw)
+	}
+
+	if wp.opts.MaxIdle > 0 && atomic.LoadInt64(&wp.idle) >= int64(wp.opts.MaxIdle) {
+		mw.Destroy()
+		return
+	}
+
+	atomic.AddInt64(&wp.idle, 1)
+	wp.pool.Put(mw)
+}
+
+// resetMagickWand removes every image from mw's sequence and resets its
+// wand-level settings, leaving it in the same state as a freshly created
+// MagickWand while keeping the C allocation (and magickWandCounter
+// accounting) alive for reuse. ClearMagickWand already does this in one
+// call; there is no need to walk the image list with MagickRemoveImage
+// first.
+func resetMagickWand(mw *MagickWand) {
+	C.ClearMagickWand(mw.wand)
+}
+
+// decrementIfPositive atomically decrements *counter by one unless it is
+// already <= 0, in which case it is left unchanged. Using a compare-and-
+// swap loop instead of an Add-then-clamp avoids the race where two
+// concurrent decrements both read a pre-clamp value and drive the
+// counter negative before either store lands.
+func decrementIfPositive(counter *int64) {
+	for {
+		current := atomic.LoadInt64(counter)
+		if current <= 0 {
+			return
+		}
+		if atomic.CompareAndSwapInt64(counter, current, current-1) {
+			return
+		}
+	}
+}
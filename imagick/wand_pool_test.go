@@ -0,0 +1,86 @@
+// Copyright 2013 Herbert G. Fischer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imagick
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWandPoolGetPut(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	wp := NewWandPool(PoolOptions{ResetOnPut: true})
+
+	mw := wp.Get()
+	if err := mw.ReadImage(`logo:`); err != nil {
+		t.Fatal(err.Error())
+	}
+	wp.Put(mw)
+
+	reused := wp.Get()
+	if reused.GetImageWidth() != 0 {
+		t.Fatalf("Expected ResetOnPut to clear the image sequence; Got width %d", reused.GetImageWidth())
+	}
+	// Bypass the pool on the way out so the wand is actually destroyed
+	// before checkGC runs, instead of sitting idle in wp.
+	reused.Destroy()
+}
+
+func TestWandPoolMaxIdleDestroysExcess(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	wp := NewWandPool(PoolOptions{MaxIdle: 1})
+
+	a := wp.Get()
+	b := wp.Get()
+
+	wp.Put(a)
+	wp.Put(b) // exceeds MaxIdle: must be destroyed, not pooled
+
+	// Drain the one wand MaxIdle allowed to stay pooled so checkGC
+	// sees every wand destroyed.
+	wp.Get().Destroy()
+}
+
+func TestWandPoolMaxConcurrency(t *testing.T) {
+	Initialize()
+	defer func(t *testing.T) {
+		checkGC(t)
+	}(t)
+	defer Terminate()
+
+	wp := NewWandPool(PoolOptions{MaxConcurrency: 2, ResetOnPut: true})
+
+	const workers = 8
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mw := wp.Get()
+			defer wp.Put(mw)
+			mw.ReadImage(`logo:`)
+		}()
+	}
+	wg.Wait()
+
+	// Drain every wand the pool accumulated so checkGC sees them all
+	// destroyed. Destroy bypasses Put, so release the MaxConcurrency
+	// slot each Get took by hand; otherwise the third Get would block.
+	for i := 0; i < workers; i++ {
+		wp.Get().Destroy()
+		<-wp.sem
+	}
+}